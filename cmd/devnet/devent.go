@@ -6,21 +6,26 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/cobra"
 	"net/netip"
+	"time"
 
 	"github.com/availproject/op-evm/cmd/server"
 	"github.com/availproject/op-evm/consensus/avail"
+	pkg_avail "github.com/availproject/op-evm/pkg/avail"
+	"github.com/availproject/op-evm/pkg/avail/simulator"
 	"github.com/availproject/op-evm/pkg/devnet"
 )
 
 func GetCommand() *cobra.Command {
 	var nodesCount, watchtowerCount int
 	var availAddr, bindInterface, accountsPath string
+	var useSimulator bool
+	var simulatorBlockTime time.Duration
 	cmd := &cobra.Command{
 		Use:   "devnet",
 		Short: "Run a devnet environment",
 		Run: func(cmd *cobra.Command, args []string) {
 			log := hclog.Default()
-			ctx, err := Run(log, nodesCount, watchtowerCount, availAddr, bindInterface, accountsPath)
+			ctx, err := Run(log, nodesCount, watchtowerCount, availAddr, useSimulator, simulatorBlockTime, bindInterface, accountsPath)
 			if err != nil {
 				log.Error("starting devnet error", "err", err)
 				return
@@ -36,17 +41,32 @@ func GetCommand() *cobra.Command {
 	cmd.Flags().IntVarP(&nodesCount, "node-count", "n", 1, "The number of sequencers")
 	cmd.Flags().IntVarP(&watchtowerCount, "watchtower-count", "w", 1, "The number of watchtowers")
 	cmd.Flags().StringVar(&availAddr, "avail-addr", "ws://127.0.0.1:9944/v1/json-rpc", "Avail JSON-RPC URL")
+	cmd.Flags().BoolVar(&useSimulator, "avail-simulator", false, "Run against an in-memory Avail simulator instead of an Avail node")
+	cmd.Flags().DurationVar(&simulatorBlockTime, "avail-simulator-block-time", 5*time.Second, "Block time of the in-memory Avail simulator")
 	cmd.Flags().StringVar(&bindInterface, "bind-addr", "127.0.0.1", "IP address of the interface to bind node ports to")
 	cmd.Flags().StringVar(&accountsPath, "account-path", "./data/test-accounts", "Path to the account mnemonic file")
 	return cmd
 }
 
-func Run(log hclog.Logger, nodesCount, watchtowerCount int, availAddr, bindInterface, accountsPath string) (*devnet.Context, error) {
+func Run(log hclog.Logger, nodesCount, watchtowerCount int, availAddr string, useSimulator bool, simulatorBlockTime time.Duration, bindInterface, accountsPath string) (*devnet.Context, error) {
 	log.Info("starting nodes")
 	bindAddr, err := netip.ParseAddr(bindInterface)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse bind interface: %w", err)
 	}
+
+	var availClient pkg_avail.Client
+	if useSimulator {
+		log.Info("using in-memory Avail simulator", "block_time", simulatorBlockTime)
+		sim := simulator.New(log, simulator.Config{BlockTime: simulatorBlockTime})
+		sim.Start()
+		availClient = sim
+	} else {
+		availClient, err = pkg_avail.NewClient(availAddr, log)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create Avail client: %w", err)
+		}
+	}
 	nodeTypes := []avail.MechanismType{avail.BootstrapSequencer}
 	for i := 0; i < nodesCount; i++ {
 		nodeTypes = append(nodeTypes, avail.Sequencer)
@@ -54,7 +74,7 @@ func Run(log hclog.Logger, nodesCount, watchtowerCount int, availAddr, bindInter
 	for i := 0; i < watchtowerCount; i++ {
 		nodeTypes = append(nodeTypes, avail.WatchTower)
 	}
-	ctx, err := devnet.StartNodes(log, bindAddr, availAddr, accountsPath, nodeTypes...)
+	ctx, err := devnet.StartNodes(log, bindAddr, availClient, accountsPath, nodeTypes...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to start devnet: %w", err)
 	}
@@ -26,12 +26,12 @@ import (
 //	}
 func GetCommand() *cobra.Command {
 	var bootnode bool
-	var availAddr, path, accountPath, fraudListenAddr string
+	var availAddr, path, accountPath, fraudListenAddr, blobCodec string
 	cmd := &cobra.Command{
 		Use:   "server",
 		Short: "Run the Optimistic EVM Rollup",
 		Run: func(cmd *cobra.Command, args []string) {
-			Run(availAddr, path, accountPath, fraudListenAddr, bootnode)
+			Run(availAddr, path, accountPath, fraudListenAddr, blobCodec, bootnode)
 		},
 	}
 	cmd.Flags().StringVar(&availAddr, "avail-addr", "ws://127.0.0.1:9944/v1/json-rpc", "Avail JSON-RPC URL")
@@ -39,15 +39,16 @@ func GetCommand() *cobra.Command {
 	cmd.Flags().StringVar(&accountPath, "account-config-file", "./configs/account", "Path to the account mnemonic file")
 	cmd.Flags().BoolVar(&bootnode, "bootstrap", false, "bootstrap flag must be specified for the first node booting a new network from the genesis")
 	cmd.Flags().StringVar(&fraudListenAddr, "fraud-srv-listen-addr", ":9990", "Fraud server listen address")
+	cmd.Flags().StringVar(&blobCodec, "avail-blob-codec", avail.CodecZstd.String(), "Compression codec of the blocks posted to Avail (none, zstd or brotli)")
 	return cmd
 }
 
 // Run initializes and starts the optimistic EVM rollup server. It takes the Avail JSON-RPC URL, a file path for
-// the configuration file, a file path for the account mnemonic file, a fraud server listen address and a bootnode
-// flag. It does not return a value.
+// the configuration file, a file path for the account mnemonic file, a fraud server listen address, the blob
+// compression codec name and a bootnode flag. It does not return a value.
 // Example usage:
-// Run("ws://127.0.0.1:9944/v1/json-rpc", "./configs/bootnode.yaml", "./configs/account", ":9990", false)
-func Run(availAddr, path, accountPath, fraudListenAddr string, bootnode bool) {
+// Run("ws://127.0.0.1:9944/v1/json-rpc", "./configs/bootnode.yaml", "./configs/account", ":9990", "zstd", false)
+func Run(availAddr, path, accountPath, fraudListenAddr, blobCodec string, bootnode bool) {
 	// Enable LibP2P logging but only >= warn
 	golog.SetAllLoggers(golog.LevelWarn)
 
@@ -59,6 +60,11 @@ func Run(availAddr, path, accountPath, fraudListenAddr string, bootnode bool) {
 	// Enable TxPool P2P gossiping
 	config.Config.Seal = true
 
+	codec, err := avail.ParseCodec(blobCodec)
+	if err != nil {
+		log.Fatalf("invalid Avail blob codec: %s", err)
+	}
+
 	availAccount, err := avail.AccountFromFile(accountPath)
 	if err != nil {
 		log.Fatalf("failed to read Avail account from %q: %s\n", accountPath, err)
@@ -74,7 +80,7 @@ func Run(availAddr, path, accountPath, fraudListenAddr string, bootnode bool) {
 		log.Fatalf("failed to get AppID from Avail: %s\n", err)
 	}
 
-	availSender := avail.NewSender(availClient, appID, availAccount)
+	availSender := avail.NewSenderWithConfig(availClient, appID, availAccount, avail.SenderConfig{Codec: codec})
 
 	cfg := consensus.Config{
 		AvailAccount:      availAccount,
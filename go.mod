@@ -6,6 +6,7 @@ replace github.com/centrifuge/go-substrate-rpc-client/v4 => github.com/availproj
 
 require (
 	github.com/0xPolygon/polygon-edge v1.0.0-rc1
+	github.com/andybalholm/brotli v1.0.4
 	github.com/armon/go-metrics v0.4.1
 	github.com/availproject/op-evm-contracts v0.0.1-alpha2
 	github.com/centrifuge/go-substrate-rpc-client/v4 v4.0.3
@@ -16,6 +17,7 @@ require (
 	github.com/hashicorp/hcl v1.0.0
 	github.com/ipfs/go-log/v2 v2.5.1
 	github.com/juju/ansiterm v1.0.0
+	github.com/klauspost/compress v1.15.12
 	github.com/libp2p/go-libp2p v0.25.0
 	github.com/multiformats/go-multiaddr v0.9.0
 	github.com/prometheus/client_golang v1.15.1
@@ -51,7 +53,6 @@ require (
 	github.com/DataDog/sketches-go v1.2.1 // indirect
 	github.com/Microsoft/go-winio v0.6.0 // indirect
 	github.com/StackExchange/wmi v0.0.0-20180116203802-5d049714c4a6 // indirect
-	github.com/andybalholm/brotli v1.0.4 // indirect
 	github.com/aws/aws-sdk-go v1.44.61 // indirect
 	github.com/benbjohnson/clock v1.3.0 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
@@ -119,7 +120,6 @@ require (
 	github.com/jackpal/go-nat-pmp v1.0.2 // indirect
 	github.com/jbenet/go-temp-err-catcher v0.1.0 // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
-	github.com/klauspost/compress v1.15.12 // indirect
 	github.com/klauspost/cpuid/v2 v2.2.1 // indirect
 	github.com/koron/go-ssdp v0.0.3 // indirect
 	github.com/libp2p/go-buffer-pool v0.1.0 // indirect
@@ -15,6 +15,20 @@ const (
 	AVL = 1_000_000_000_000_000_000
 )
 
+// AccountManager is implemented by Avail clients that keep account state on
+// their own instead of exposing it through Avail runtime storage and
+// extrinsics (e.g. the in-memory simulator).
+type AccountManager interface {
+	// AccountExists reports whether the account with the public key is known.
+	AccountExists(publicKey []byte) (bool, error)
+
+	// FreeBalance returns the free balance of the account, in Avail fractions.
+	FreeBalance(publicKey []byte) (*big.Int, error)
+
+	// Transfer funds the account with the amount of Avail fractions.
+	Transfer(publicKey []byte, amount *big.Int) error
+}
+
 // NewAccount generates a new Avail account by creating a mnemonic phrase and deriving the key pair.
 // It returns the generated key pair and an error if there is an issue.
 func NewAccount() (signature.KeyringPair, error) {
@@ -71,6 +85,10 @@ func AccountExistsFromMnemonic(client Client, filePath string) (bool, error) {
 		return false, err
 	}
 
+	if am, ok := client.(AccountManager); ok {
+		return am.AccountExists(account.PublicKey)
+	}
+
 	api, err := instance(client)
 	if err != nil {
 		return false, err
@@ -94,6 +112,10 @@ func AccountExistsFromMnemonic(client Client, filePath string) (bool, error) {
 // It takes a client, the account key pair, the amount to deposit, and the nonce increment.
 // It returns an error if there is an issue.
 func DepositBalance(client Client, account signature.KeyringPair, amount, nonceIncrement uint64) error {
+	if am, ok := client.(AccountManager); ok {
+		return am.Transfer(account.PublicKey, new(big.Int).SetUint64(amount))
+	}
+
 	api, err := instance(client)
 	if err != nil {
 		return err
@@ -193,6 +215,15 @@ func DepositBalance(client Client, account signature.KeyringPair, amount, nonceI
 // GetBalance retrieves the Avail token balance of the specified account.
 // It takes a client and the account key pair, and returns the account balance as a *big.Int and an error if there is an issue.
 func GetBalance(client Client, account signature.KeyringPair) (*big.Int, error) {
+	if am, ok := client.(AccountManager); ok {
+		free, err := am.FreeBalance(account.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return new(big.Int).Div(free, big.NewInt(AVL)), nil
+	}
+
 	api, err := instance(client)
 	if err != nil {
 		return nil, err
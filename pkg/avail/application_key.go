@@ -25,6 +25,17 @@ var (
 	ErrAppIDNotFound = errors.New("AppID not found")
 )
 
+// ApplicationKeyRegistry is implemented by Avail clients that manage
+// application keys on their own instead of through the DataAvailability
+// runtime pallet (e.g. the in-memory simulator).
+type ApplicationKeyRegistry interface {
+	// QueryAppID returns the AppID of the application key, or ErrAppIDNotFound.
+	QueryAppID(applicationKey string) (types.UCompact, error)
+
+	// CreateApplicationKey registers the application key for the owner and returns its AppID.
+	CreateApplicationKey(applicationKey string, owner []byte) (types.UCompact, error)
+}
+
 // EnsureApplicationKeyExists checks if the application key exists on the blockchain. If it doesn't exist, it creates a new application key.
 // It takes a client, the application key string, and the signing key pair.
 // It returns the AppID and an error if there is an issue.
@@ -46,6 +57,10 @@ func EnsureApplicationKeyExists(client Client, applicationKey string, signingKey
 // It takes a client and the application key string.
 // It returns the AppID and an error if there is an issue.
 func QueryAppID(client Client, applicationKey string) (types.UCompact, error) {
+	if r, ok := client.(ApplicationKeyRegistry); ok {
+		return r.QueryAppID(applicationKey)
+	}
+
 	api, err := instance(client)
 	if err != nil {
 		return types.NewUCompactFromUInt(0), err
@@ -88,6 +103,10 @@ func QueryAppID(client Client, applicationKey string) (types.UCompact, error) {
 // It takes a client, the application key string, and the signing key pair.
 // It returns the AppID and an error if there is an issue.
 func CreateApplicationKey(client Client, applicationKey string, signingKeyPair signature.KeyringPair) (types.UCompact, error) {
+	if r, ok := client.(ApplicationKeyRegistry); ok {
+		return r.CreateApplicationKey(applicationKey, signingKeyPair.PublicKey)
+	}
+
 	api, err := instance(client)
 	if err != nil {
 		return types.NewUCompactFromUInt(0), err
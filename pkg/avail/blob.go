@@ -12,21 +12,45 @@ const (
 	// BlobMagic is required to be present in a `Batch` read from Avail.
 	BlobMagic = byte(0b10101010)
 
+	// BlobMagicV2 identifies the versioned blob envelope, which carries the
+	// payload kind, the compression codec and the uncompressed data length
+	// next to the data.
+	BlobMagicV2 = byte(0b10101011)
+
 	// MaxBlobSize defines the maximum length for stored data in a blob.
 	MaxBlobSize = 1 << 24 // 2^24 = 16MB
 )
 
+// BlobKind identifies the type of the payload carried in a blob.
+type BlobKind byte
+
+const (
+	// BlobKindBlock is a single RLP encoded L2 block.
+	BlobKindBlock BlobKind = iota
+)
+
 var (
 	// ErrDataTooLong is the error returned when the data length exceeds the maximum limit.
 	ErrDataTooLong = errors.New("data length exceeds maximum limit")
 
 	// ErrInvalidBlobMagic is the error returned when the blob magic byte is invalid.
 	ErrInvalidBlobMagic = errors.New("invalid blob magic")
+
+	// ErrUnsupportedBlobFormat is the error returned when the blob fields are
+	// not supported by the blob format version.
+	ErrUnsupportedBlobFormat = errors.New("unsupported blob format")
 )
 
 // Blob is a wrapper type for data that is stored in Avail.
+//
+// Blobs with BlobMagic hold the raw data. Blobs with BlobMagicV2 are encoded
+// as `magic | kind | codec | compact(uncompressed length) | compact(length) |
+// compressed data`; Data always holds the uncompressed bytes and the
+// compression is applied on Encode and reverted on Decode.
 type Blob struct {
 	Magic byte
+	Kind  BlobKind
+	Codec Codec
 	Data  []byte
 }
 
@@ -34,8 +58,14 @@ type Blob struct {
 func (b *Blob) Encode(e scale.Encoder) error {
 	var err error
 
-	if b.Magic != BlobMagic {
-		return fmt.Errorf("%w got %d, expected %d", ErrInvalidBlobMagic, b.Magic, BlobMagic)
+	switch b.Magic {
+	case BlobMagic:
+		if b.Kind != BlobKindBlock || b.Codec != CodecNone {
+			return fmt.Errorf("%w: kind %d and codec %s require blob magic %d", ErrUnsupportedBlobFormat, b.Kind, b.Codec, BlobMagicV2)
+		}
+	case BlobMagicV2:
+	default:
+		return fmt.Errorf("%w got %d, expected %d or %d", ErrInvalidBlobMagic, b.Magic, BlobMagic, BlobMagicV2)
 	}
 
 	if len(b.Data) > MaxBlobSize {
@@ -47,12 +77,39 @@ func (b *Blob) Encode(e scale.Encoder) error {
 		return err
 	}
 
-	err = e.EncodeUintCompact(*big.NewInt(int64(len(b.Data))))
+	data := b.Data
+	if b.Magic == BlobMagicV2 {
+		data, err = b.Codec.compress(b.Data)
+		if err != nil {
+			return err
+		}
+
+		if len(data) > MaxBlobSize {
+			return ErrDataTooLong
+		}
+
+		err = e.PushByte(byte(b.Kind))
+		if err != nil {
+			return err
+		}
+
+		err = e.PushByte(byte(b.Codec))
+		if err != nil {
+			return err
+		}
+
+		err = e.EncodeUintCompact(*big.NewInt(int64(len(b.Data))))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = e.EncodeUintCompact(*big.NewInt(int64(len(data))))
 	if err != nil {
 		return err
 	}
 
-	err = e.Write(b.Data)
+	err = e.Write(data)
 	if err != nil {
 		return err
 	}
@@ -69,28 +126,75 @@ func (b *Blob) Decode(d scale.Decoder) error {
 		return err
 	}
 
-	if b.Magic != BlobMagic {
-		return fmt.Errorf("%w got %d, expected %d", ErrInvalidBlobMagic, b.Magic, BlobMagic)
+	switch b.Magic {
+	case BlobMagic:
+		b.Kind = BlobKindBlock
+		b.Codec = CodecNone
+
+		b.Data, err = decodeBlobBytes(d)
+		return err
+
+	case BlobMagicV2:
+		kind, err := d.ReadOneByte()
+		if err != nil {
+			return err
+		}
+		b.Kind = BlobKind(kind)
+
+		codec, err := d.ReadOneByte()
+		if err != nil {
+			return err
+		}
+		b.Codec = Codec(codec)
+
+		size, err := decodeBlobLength(d)
+		if err != nil {
+			return err
+		}
+
+		data, err := decodeBlobBytes(d)
+		if err != nil {
+			return err
+		}
+
+		b.Data, err = b.Codec.decompress(data, size)
+		return err
+
+	default:
+		return fmt.Errorf("%w got %d, expected %d or %d", ErrInvalidBlobMagic, b.Magic, BlobMagic, BlobMagicV2)
 	}
+}
 
+// decodeBlobLength decodes a compact encoded length and checks it against MaxBlobSize.
+func decodeBlobLength(d scale.Decoder) (int, error) {
 	data_len, err := d.DecodeUintCompact()
 	if err != nil || data_len == nil {
-		return fmt.Errorf("invalid length (%s)", err.Error())
+		return 0, fmt.Errorf("invalid length (%v)", err)
 	}
 
 	if !data_len.IsInt64() {
-		return fmt.Errorf("corrupted length (is not int64)")
+		return 0, fmt.Errorf("corrupted length (is not int64)")
 	}
 
 	if data_len.Int64() > MaxBlobSize {
-		return ErrDataTooLong
+		return 0, ErrDataTooLong
 	}
 
-	b.Data = make([]byte, data_len.Int64())
-	err = d.Read(b.Data)
+	return int(data_len.Int64()), nil
+}
+
+// decodeBlobBytes decodes length prefixed bytes.
+func decodeBlobBytes(d scale.Decoder) ([]byte, error) {
+	size, err := decodeBlobLength(d)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	data := make([]byte, size)
+	err = d.Read(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
 }
@@ -57,60 +57,107 @@ func Test_BlobEncoding(t *testing.T) {
 		},
 	}
 
-	for i, tc := range testCases {
-		ok := t.Run(fmt.Sprintf("case %d: %s", i, tc.name), func(t *testing.T) {
-			input := Blob{
-				Magic: BlobMagic,
-				Data:  tc.input,
-			}
+	formats := []struct {
+		name  string
+		magic byte
+		codec Codec
+	}{
+		{name: "legacy", magic: BlobMagic, codec: CodecNone},
+		{name: "v2 none", magic: BlobMagicV2, codec: CodecNone},
+		{name: "v2 zstd", magic: BlobMagicV2, codec: CodecZstd},
+		{name: "v2 brotli", magic: BlobMagicV2, codec: CodecBrotli},
+	}
 
-			buf := bytes.NewBuffer(nil)
-
-			// Encode Blob
-			{
-				encoder := scale.NewEncoder(buf)
-				err := input.Encode(*encoder)
-				switch {
-				case err == nil && tc.encodeErrMatcher == nil:
-					// correct; carry on
-				case err != nil && tc.encodeErrMatcher == nil:
-					t.Fatalf("error == %#v, want nil", err)
-				case err == nil && tc.encodeErrMatcher != nil:
-					t.Fatalf("error == nil, want non-nil")
-				case !tc.encodeErrMatcher(err):
-					t.Fatalf("error == %#v, want matching", err)
-				case tc.encodeErrMatcher(err):
-					// When encoding is expected to return an error, decoding
-					// couldn't work after that, so test case need to "short
-					// circuit" here.
-					return
+	for i, tc := range testCases {
+		for _, f := range formats {
+			tc, f := tc, f
+			ok := t.Run(fmt.Sprintf("case %d: %s (%s)", i, tc.name, f.name), func(t *testing.T) {
+				input := Blob{
+					Magic: f.magic,
+					Codec: f.codec,
+					Data:  tc.input,
 				}
-			}
 
-			// Decode blob
-			{
-				var output Blob
-				decoder := scale.NewDecoder(buf)
-				err := output.Decode(*decoder)
-				switch {
-				case err == nil && tc.decodeErrMatcher == nil:
-					// correct; carry on
-				case err != nil && tc.decodeErrMatcher == nil:
-					t.Fatalf("error == %#v, want nil", err)
-				case err == nil && tc.decodeErrMatcher != nil:
-					t.Fatalf("error == nil, want non-nil")
-				case !tc.decodeErrMatcher(err):
-					t.Fatalf("error == %#v, want matching", err)
+				buf := bytes.NewBuffer(nil)
+
+				// Encode Blob
+				{
+					encoder := scale.NewEncoder(buf)
+					err := input.Encode(*encoder)
+					switch {
+					case err == nil && tc.encodeErrMatcher == nil:
+						// correct; carry on
+					case err != nil && tc.encodeErrMatcher == nil:
+						t.Fatalf("error == %#v, want nil", err)
+					case err == nil && tc.encodeErrMatcher != nil:
+						t.Fatalf("error == nil, want non-nil")
+					case !tc.encodeErrMatcher(err):
+						t.Fatalf("error == %#v, want matching", err)
+					case tc.encodeErrMatcher(err):
+						// When encoding is expected to return an error, decoding
+						// couldn't work after that, so test case need to "short
+						// circuit" here.
+						return
+					}
 				}
 
-				if !cmp.Equal(input.Data, output.Data, cmpopts.EquateEmpty()) {
-					t.Fatalf("input & output bytes don't match: expected %v, got %v", input.Data, output.Data)
+				// Decode blob
+				{
+					var output Blob
+					decoder := scale.NewDecoder(buf)
+					err := output.Decode(*decoder)
+					switch {
+					case err == nil && tc.decodeErrMatcher == nil:
+						// correct; carry on
+					case err != nil && tc.decodeErrMatcher == nil:
+						t.Fatalf("error == %#v, want nil", err)
+					case err == nil && tc.decodeErrMatcher != nil:
+						t.Fatalf("error == nil, want non-nil")
+					case !tc.decodeErrMatcher(err):
+						t.Fatalf("error == %#v, want matching", err)
+					}
+
+					if !cmp.Equal(input.Data, output.Data, cmpopts.EquateEmpty()) {
+						t.Fatalf("input & output bytes don't match: expected %v, got %v", input.Data, output.Data)
+					}
+
+					if output.Codec != input.Codec {
+						t.Fatalf("codec == %s, want %s", output.Codec, input.Codec)
+					}
 				}
+			})
+
+			if !ok {
+				t.Logf("random seed: %d", *test.Seed)
 			}
-		})
+		}
+	}
+}
+
+func Test_BlobCompression(t *testing.T) {
+	data := bytes.Repeat([]byte("op-evm compressible block data "), 1<<10)
+
+	for _, codec := range []Codec{CodecZstd, CodecBrotli} {
+		buf := bytes.NewBuffer(nil)
+		input := Blob{Magic: BlobMagicV2, Codec: codec, Data: data}
+		if err := input.Encode(*scale.NewEncoder(buf)); err != nil {
+			t.Fatalf("%s: encode error: %s", codec, err)
+		}
 
-		if !ok {
-			t.Logf("random seed: %d", *test.Seed)
+		if buf.Len() >= len(data)/4 {
+			t.Fatalf("%s: encoded blob is %d bytes, want less than a quarter of %d", codec, buf.Len(), len(data))
 		}
 	}
+
+	// Legacy blobs can't carry a codec.
+	input := Blob{Magic: BlobMagic, Codec: CodecZstd, Data: data}
+	if err := input.Encode(*scale.NewEncoder(bytes.NewBuffer(nil))); !errors.Is(err, ErrUnsupportedBlobFormat) {
+		t.Fatalf("error == %#v, want ErrUnsupportedBlobFormat", err)
+	}
+
+	// Unknown codecs are rejected.
+	input = Blob{Magic: BlobMagicV2, Codec: Codec(0xff), Data: data}
+	if err := input.Encode(*scale.NewEncoder(bytes.NewBuffer(nil))); !errors.Is(err, ErrUnknownCodec) {
+		t.Fatalf("error == %#v, want ErrUnknownCodec", err)
+	}
 }
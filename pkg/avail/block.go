@@ -95,6 +95,11 @@ func BlockFromAvail(avail_blk *types.SignedBlock, appID types.UCompact, callIdx
 			}
 		}
 
+		if blob.Kind != BlobKindBlock {
+			logger.Info("unsupported blob kind", "avail_block_number", avail_blk.Block.Header.Number, "extrinsic_index", i, "blob_kind", blob.Kind)
+			continue
+		}
+
 		blk := edge_types.Block{}
 		if err := blk.UnmarshalRLP(blob.Data); err != nil {
 			return nil, err
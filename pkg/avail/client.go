@@ -26,6 +26,14 @@ type Client interface {
 	SearchBlock(offset int64, searchFunc SearchFunc) (*types.SignedBlock, error)
 }
 
+// CallIndexResolver is implemented by Avail clients that resolve the
+// `submit_data` call index on their own, without the runtime metadata of a
+// live Avail node (e.g. the in-memory simulator).
+type CallIndexResolver interface {
+	// SubmitDataCallIndex returns the call index of CallSubmitData.
+	SubmitDataCallIndex() (types.CallIndex, error)
+}
+
 // client is an implementation of the Client interface.
 type client struct {
 	api         *gsrpc.SubstrateAPI
@@ -112,6 +120,23 @@ func (c *client) GetLatestHeader() (*types.Header, error) {
 	return c.api.RPC.Chain.GetHeaderLatest()
 }
 
+// GetBlockByNumber retrieves the Avail block at the specified height.
+//
+// Parameters:
+//   - number: The Avail block number.
+//
+// Return:
+//   - *types.SignedBlock: The block.
+//   - error: An error if the retrieval fails.
+func (c *client) GetBlockByNumber(number uint64) (*types.SignedBlock, error) {
+	blkHash, err := c.api.RPC.Chain.GetBlockHash(number)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.api.RPC.Chain.GetBlock(blkHash)
+}
+
 // FindCallIndex finds the call index for CallSubmitData in the Avail network.
 //
 // Parameters:
@@ -121,6 +146,10 @@ func (c *client) GetLatestHeader() (*types.Header, error) {
 //   - types.CallIndex: The call index for CallSubmitData.
 //   - error: An error if the call index retrieval fails.
 func FindCallIndex(client Client) (types.CallIndex, error) {
+	if r, ok := client.(CallIndexResolver); ok {
+		return r.SubmitDataCallIndex()
+	}
+
 	api, err := instance(client)
 	if err == ErrUnsupportedClient {
 		return types.CallIndex{}, nil
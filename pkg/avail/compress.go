@@ -0,0 +1,144 @@
+package avail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec identifies the compression algorithm of the blob data.
+type Codec byte
+
+const (
+	// CodecNone stores blob data uncompressed.
+	CodecNone Codec = iota
+
+	// CodecZstd compresses blob data with Zstandard.
+	CodecZstd
+
+	// CodecBrotli compresses blob data with Brotli.
+	CodecBrotli
+)
+
+// ErrUnknownCodec is returned when the blob codec is not supported.
+var ErrUnknownCodec = errors.New("unknown blob codec")
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// ParseCodec parses the codec name ("none", "zstd" or "brotli").
+func ParseCodec(name string) (Codec, error) {
+	switch name {
+	case "", "none":
+		return CodecNone, nil
+	case "zstd":
+		return CodecZstd, nil
+	case "brotli":
+		return CodecBrotli, nil
+	default:
+		return CodecNone, fmt.Errorf("%w: %q", ErrUnknownCodec, name)
+	}
+}
+
+// String returns the codec name.
+func (c Codec) String() string {
+	switch c {
+	case CodecNone:
+		return "none"
+	case CodecZstd:
+		return "zstd"
+	case CodecBrotli:
+		return "brotli"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(c))
+	}
+}
+
+// compress compresses the data with the codec.
+func (c Codec) compress(data []byte) ([]byte, error) {
+	switch c {
+	case CodecNone:
+		return data, nil
+
+	case CodecZstd:
+		if err := initZstd(); err != nil {
+			return nil, err
+		}
+		return zstdEncoder.EncodeAll(data, nil), nil
+
+	case CodecBrotli:
+		var buf bytes.Buffer
+		w := brotli.NewWriterLevel(&buf, brotli.DefaultCompression)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCodec, byte(c))
+	}
+}
+
+// decompress decompresses the data with the codec. The decompressed data must
+// be exactly size bytes long, which also caps the memory spent on
+// maliciously crafted blobs.
+func (c Codec) decompress(data []byte, size int) ([]byte, error) {
+	var (
+		out []byte
+		err error
+	)
+
+	switch c {
+	case CodecNone:
+		out = data
+
+	case CodecZstd:
+		if err = initZstd(); err != nil {
+			return nil, err
+		}
+		out, err = zstdDecoder.DecodeAll(data, make([]byte, 0, size))
+
+	case CodecBrotli:
+		out, err = io.ReadAll(io.LimitReader(brotli.NewReader(bytes.NewReader(data)), int64(size)+1))
+
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCodec, byte(c))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(out) != size {
+		return nil, fmt.Errorf("decompressed length mismatch: got %d, expected %d", len(out), size)
+	}
+
+	return out, nil
+}
+
+// initZstd lazily initializes the shared zstd encoder and decoder. Both are
+// safe for concurrent use through EncodeAll/DecodeAll.
+func initZstd() error {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr != nil {
+			return
+		}
+
+		zstdDecoder, zstdErr = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(MaxBlobSize))
+	})
+
+	return zstdErr
+}
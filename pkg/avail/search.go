@@ -12,6 +12,17 @@ import (
 // something went wrong.
 type SearchFunc func(*types.SignedBlock) (int64, bool, error)
 
+// BlockFetcher is the minimal block access needed for searching the Avail
+// chain. It is implemented by the gsrpc backed client as well as by
+// alternative Client implementations.
+type BlockFetcher interface {
+	// GetLatestHeader retrieves the latest header from the Avail network.
+	GetLatestHeader() (*types.Header, error)
+
+	// GetBlockByNumber retrieves the Avail block at the specified height.
+	GetBlockByNumber(number uint64) (*types.SignedBlock, error)
+}
+
 // SearchBlock searches for a block at the specified offset using the provided search function.
 //
 // Parameters:
@@ -22,22 +33,32 @@ type SearchFunc func(*types.SignedBlock) (int64, bool, error)
 //   - *types.SignedBlock: The found block.
 //   - error: An error if the block search fails.
 func (c *client) SearchBlock(offset int64, searchFunc SearchFunc) (*types.SignedBlock, error) {
+	return SearchBlocks(c, offset, searchFunc)
+}
+
+// SearchBlocks walks the Avail chain available through the fetcher, starting
+// at the specified offset, until the search function reports a match.
+//
+// Parameters:
+//   - fetcher: The source of Avail blocks.
+//   - offset: The offset from the current block to start the search. If offset is 0, it starts from the latest block.
+//   - searchFunc: The search function that determines the seek offset based on the current Avail block.
+//
+// Return:
+//   - *types.SignedBlock: The found block.
+//   - error: An error if the block search fails.
+func SearchBlocks(fetcher BlockFetcher, offset int64, searchFunc SearchFunc) (*types.SignedBlock, error) {
 	// In case offset is zero, it means that we have new chain node and we need to sync it
 	// from latest head in avail towards first block.
 	if offset == 0 {
-		header, err := c.api.RPC.Chain.GetHeaderLatest()
+		header, err := fetcher.GetLatestHeader()
 		if err != nil {
 			return nil, err
 		}
 		offset = int64(header.Number)
 	}
 
-	blkHash, err := c.api.RPC.Chain.GetBlockHash(uint64(offset))
-	if err != nil {
-		return nil, err
-	}
-
-	blk, err := c.api.RPC.Chain.GetBlock(blkHash)
+	blk, err := fetcher.GetBlockByNumber(uint64(offset))
 	if err != nil {
 		return nil, err
 	}
@@ -58,12 +79,7 @@ func (c *client) SearchBlock(offset int64, searchFunc SearchFunc) (*types.Signed
 			break
 		}
 
-		blkHash, err := c.api.RPC.Chain.GetBlockHash(uint64(blk.Block.Header.Number) + uint64(offset))
-		if err != nil {
-			return nil, err
-		}
-
-		blk, err = c.api.RPC.Chain.GetBlock(blkHash)
+		blk, err = fetcher.GetBlockByNumber(uint64(blk.Block.Header.Number) + uint64(offset))
 		if err != nil {
 			return nil, err
 		}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	edgetypes "github.com/0xPolygon/polygon-edge/types"
+	"github.com/armon/go-metrics"
 	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
@@ -23,6 +24,14 @@ type Sender interface {
 	SendAndWaitForStatus(blk *edgetypes.Block, status types.ExtrinsicStatus) error
 }
 
+// DataSubmitter is implemented by Avail clients that accept encoded
+// `submit_data` payloads directly, without a signed extrinsic going through
+// gsrpc (e.g. the in-memory simulator). The returned channel delivers the
+// extrinsic status updates, the same way an Avail extrinsic subscription does.
+type DataSubmitter interface {
+	SubmitData(appID types.UCompact, signer signature.KeyringPair, data []byte) (<-chan types.ExtrinsicStatus, error)
+}
+
 // Result represents the final result of block data submission.
 type Result struct{}
 
@@ -45,22 +54,36 @@ func NewBlackholeSender() Sender {
 	return &blackholeSender{}
 }
 
+// SenderConfig holds the optional settings of the block data sender.
+type SenderConfig struct {
+	// Codec is the compression codec applied to the posted blocks.
+	Codec Codec
+}
+
 // sender is an implementation of Sender for Avail block data submission.
 type sender struct {
 	appID          types.UCompact
 	client         Client
 	signingKeyPair signature.KeyringPair
 	nextNonce      uint64
+	config         SenderConfig
 }
 
 // NewSender constructs a block data sender for Avail.
 // It takes a Client instance, appID of type types.UCompact, and a signingKeyPair of type signature.KeyringPair.
-// It returns a Sender instance.
+// It returns a Sender instance that posts uncompressed blobs.
 func NewSender(client Client, appID types.UCompact, signingKeyPair signature.KeyringPair) Sender {
+	return NewSenderWithConfig(client, appID, signingKeyPair, SenderConfig{})
+}
+
+// NewSenderWithConfig constructs a block data sender for Avail with the
+// settings from the config, such as the blob compression codec.
+func NewSenderWithConfig(client Client, appID types.UCompact, signingKeyPair signature.KeyringPair, config SenderConfig) Sender {
 	return &sender{
 		appID:          appID,
 		client:         client,
 		signingKeyPair: signingKeyPair,
+		config:         config,
 	}
 }
 
@@ -68,6 +91,16 @@ func NewSender(client Client, appID types.UCompact, signingKeyPair signature.Key
 // It takes a blk parameter of type *edgetypes.Block.
 // It returns an error if there was a problem sending the data.
 func (s *sender) Send(blk *edgetypes.Block) error {
+	if ds, ok := s.client.(DataSubmitter); ok {
+		data, err := s.encodeBlockData(blk)
+		if err != nil {
+			return err
+		}
+
+		_, err = ds.SubmitData(s.appID, s.signingKeyPair, data)
+		return err
+	}
+
 	api, err := instance(s.client)
 	if err != nil {
 		return err
@@ -92,11 +125,25 @@ func (s *sender) Send(blk *edgetypes.Block) error {
 func (s *sender) SendAndWaitForStatus(blk *edgetypes.Block, dstatus types.ExtrinsicStatus) error {
 	// Only these three are supported for now.
 	// NOTE: If adding new types here, handle them correspondingly in the end of
-	//       the function as well!
+	//       waitForStatus() as well!
 	if !dstatus.IsFinalized && !dstatus.IsReady && !dstatus.IsInBlock {
 		return fmt.Errorf("unsupported extrinsic status expectation: %#v", dstatus)
 	}
 
+	if ds, ok := s.client.(DataSubmitter); ok {
+		data, err := s.encodeBlockData(blk)
+		if err != nil {
+			return err
+		}
+
+		statusCh, err := ds.SubmitData(s.appID, s.signingKeyPair, data)
+		if err != nil {
+			return err
+		}
+
+		return waitForStatus(statusCh, nil, dstatus)
+	}
+
 	api, err := instance(s.client)
 	if err != nil {
 		return err
@@ -114,14 +161,21 @@ func (s *sender) SendAndWaitForStatus(blk *edgetypes.Block, dstatus types.Extrin
 
 	defer sub.Unsubscribe()
 
+	return waitForStatus(sub.Chan(), sub.Err(), dstatus)
+}
+
+// waitForStatus consumes extrinsic status updates until the expected status
+// is reached, the extrinsic is dropped or invalidated, or the subscription
+// fails. A nil errCh is never selected.
+func waitForStatus(statusCh <-chan types.ExtrinsicStatus, errCh <-chan error, dstatus types.ExtrinsicStatus) error {
 	for {
 		select {
-		case status := <-sub.Chan():
-			_, err := dstatus.MarshalJSON()
-			if err != nil {
-				panic(err)
+		case status, ok := <-statusCh:
+			if !ok {
+				return fmt.Errorf("extrinsic status channel closed before reaching expected status")
 			}
-			// NOTE: See first line of this function for supported extrinsic status expectations.
+
+			// NOTE: See first line of SendAndWaitForStatus() for supported extrinsic status expectations.
 			switch {
 			case dstatus.IsFinalized && status.IsFinalized:
 				return nil
@@ -134,13 +188,51 @@ func (s *sender) SendAndWaitForStatus(blk *edgetypes.Block, dstatus types.Extrin
 					return fmt.Errorf("unexpected extrinsic status from Avail: %#v", status)
 				}
 			}
-		case err := <-sub.Err():
+		case err := <-errCh:
 			// TODO: Consider re-connecting subscription channel on error?
 			return err
 		}
 	}
 }
 
+// encodeBlockData encodes the block into the `submit_data` payload posted to
+// Avail, compressed with the configured codec.
+func (s *sender) encodeBlockData(blk *edgetypes.Block) ([]byte, error) {
+	blob := Blob{
+		Magic: BlobMagicV2,
+		Kind:  BlobKindBlock,
+		Codec: s.config.Codec,
+		Data:  blk.MarshalRLP(),
+	}
+
+	// XXX: This encoding process is an inefficient hack to workaround
+	// problem in the encoding pipeline from client code to Avail server.
+	// `Blob` implements `scale.Encodeable` interface, but it it's passed
+	// directly to `types.NewCall()`, the server will return an error. This
+	// requires further investigation to fix.
+	data, err := codec.Encode(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	recordBlobMetrics(s.config.Codec, len(blob.Data), len(data))
+
+	return data, nil
+}
+
+// recordBlobMetrics records the raw and encoded sizes of a posted blob and
+// the bytes saved by compression.
+func recordBlobMetrics(c Codec, rawSize, encodedSize int) {
+	labels := []metrics.Label{{Name: "codec", Value: c.String()}}
+
+	metrics.IncrCounterWithLabels([]string{"avail", "sender", "blob_raw_bytes"}, float32(rawSize), labels)
+	metrics.IncrCounterWithLabels([]string{"avail", "sender", "blob_encoded_bytes"}, float32(encodedSize), labels)
+
+	if saved := rawSize - encodedSize; saved > 0 {
+		metrics.IncrCounterWithLabels([]string{"avail", "sender", "blob_bytes_saved"}, float32(saved), labels)
+	}
+}
+
 // prepareExtrinsicForSend prepares the extrinsic for sending the block data.
 // It takes api parameter of type *gsrpc.SubstrateAPI and blk parameter of type *edgetypes.Block.
 // It returns a types.Extrinsic and an error if there was a problem preparing the extrinsic.
@@ -150,19 +242,9 @@ func (s *sender) prepareExtrinsicForSend(api *gsrpc.SubstrateAPI, blk *edgetypes
 		return types.Extrinsic{}, err
 	}
 
-	blob := Blob{
-		Magic: BlobMagic,
-		Data:  blk.MarshalRLP(),
-	}
-
 	var call types.Call
 	{
-		// XXX: This encoding process is an inefficient hack to workaround
-		// problem in the encoding pipeline from client code to Avail server.
-		// `Blob` implements `scale.Encodeable` interface, but it it's passed
-		// directly to `types.NewCall()`, the server will return an error. This
-		// requires further investigation to fix.
-		encodedBytes, err := codec.Encode(blob)
+		encodedBytes, err := s.encodeBlockData(blk)
 		if err != nil {
 			return types.Extrinsic{}, err
 		}
@@ -0,0 +1,573 @@
+// Package simulator provides an in-memory Avail node that implements
+// avail.Client and avail.Sender without any outside services. It is meant for
+// hermetic tests and local devnets: blocks are produced on a controllable
+// clock, submitted data is included as `submit_data` extrinsics carrying the
+// sender's AppID and call index, and delays, dropped extrinsics and forks can
+// be injected at will.
+package simulator
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/availproject/op-evm/pkg/avail"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/hashicorp/go-hclog"
+)
+
+var (
+	// DefaultCallIndex is the `submit_data` call index used by the simulator
+	// when none is configured.
+	DefaultCallIndex = types.CallIndex{SectionIndex: 29, MethodIndex: 1}
+
+	// ErrClosed is returned when the simulator has been closed.
+	ErrClosed = errors.New("avail simulator closed")
+
+	// ErrBlockNotFound is returned when the requested block doesn't exist.
+	ErrBlockNotFound = errors.New("block not found")
+
+	// ErrForkBeyondFinality is returned when a fork would replace finalized blocks.
+	ErrForkBeyondFinality = errors.New("fork depth reaches beyond finalized head")
+)
+
+// Config holds the simulator configuration.
+type Config struct {
+	// BlockTime is the interval of automatic block production after Start().
+	// When zero, blocks are produced only by explicit calls to Tick().
+	BlockTime time.Duration
+
+	// FinalityDepth is the number of blocks on top of a block before it's
+	// considered final. Zero finalizes blocks immediately on inclusion, which
+	// also rules out forks.
+	FinalityDepth uint64
+
+	// CallIndex is the `submit_data` call index attached to the included
+	// extrinsics. DefaultCallIndex is used when left empty.
+	CallIndex types.CallIndex
+}
+
+// submission tracks a single extrinsic from submission until finality.
+type submission struct {
+	ext       types.Extrinsic
+	includeAt uint64
+	inBlock   uint64
+	statusCh  chan types.ExtrinsicStatus
+}
+
+// notify delivers the status update without ever blocking block production.
+func (s *submission) notify(status types.ExtrinsicStatus) {
+	select {
+	case s.statusCh <- status:
+	default:
+	}
+}
+
+// Simulator is an in-memory Avail node.
+type Simulator struct {
+	logger        hclog.Logger
+	blockTime     time.Duration
+	finalityDepth uint64
+	callIdx       types.CallIndex
+
+	mtx      sync.Mutex
+	blocks   []*types.SignedBlock
+	hashes   []types.Hash
+	pending  []*submission
+	included []*submission
+	headCh   chan struct{}
+	forkSalt uint64
+
+	dropNext       int
+	inclusionDelay uint64
+
+	balances  map[string]*big.Int
+	nonces    map[string]uint64
+	appKeys   map[string]types.UCompact
+	nextAppID uint64
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	closeCh   chan struct{}
+}
+
+// New constructs a new Avail simulator with only the genesis block in it.
+func New(logger hclog.Logger, config Config) *Simulator {
+	callIdx := config.CallIndex
+	if callIdx == (types.CallIndex{}) {
+		callIdx = DefaultCallIndex
+	}
+
+	s := &Simulator{
+		logger:        logger.Named("avail_simulator"),
+		blockTime:     config.BlockTime,
+		finalityDepth: config.FinalityDepth,
+		callIdx:       callIdx,
+		headCh:        make(chan struct{}),
+		balances:      make(map[string]*big.Int),
+		nonces:        make(map[string]uint64),
+		appKeys:       make(map[string]types.UCompact),
+		nextAppID:     1,
+		closeCh:       make(chan struct{}),
+	}
+
+	s.appendBlock(nil)
+
+	return s
+}
+
+// Start begins automatic block production every Config.BlockTime. It is a
+// no-op when BlockTime is zero.
+func (s *Simulator) Start() {
+	if s.blockTime == 0 {
+		return
+	}
+
+	s.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(s.blockTime)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					s.Tick()
+				case <-s.closeCh:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// Close stops block production and terminates all block streams.
+func (s *Simulator) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+	})
+}
+
+// Tick produces a single block with all the pending extrinsics that are due
+// for inclusion and returns its number.
+func (s *Simulator) Tick() uint64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.produceBlock()
+}
+
+// Advance produces n blocks and returns the number of the last one.
+func (s *Simulator) Advance(n int) uint64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var head uint64
+	for i := 0; i < n; i++ {
+		head = s.produceBlock()
+	}
+
+	return head
+}
+
+// DropNext makes the simulator drop the next n submitted extrinsics. Their
+// submitters receive an `IsDropped` status.
+func (s *Simulator) DropNext(n int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.dropNext = n
+}
+
+// SetInclusionDelay delays the inclusion of subsequently submitted
+// extrinsics by the given number of blocks.
+func (s *Simulator) SetInclusionDelay(blocks uint64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.inclusionDelay = blocks
+}
+
+// Fork replaces the last depth blocks with a new, one block longer branch.
+// Extrinsics from the replaced blocks are retracted; when reinclude is set
+// they are included again on the new branch, otherwise they are dropped.
+func (s *Simulator) Fork(depth uint64, reinclude bool) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	head := s.head()
+	if depth == 0 {
+		return nil
+	}
+
+	if depth > s.finalityDepth || depth > head {
+		return ErrForkBeyondFinality
+	}
+
+	forkPoint := head - depth + 1
+
+	var retracted, kept []*submission
+	for _, sub := range s.included {
+		if sub.inBlock >= forkPoint {
+			retracted = append(retracted, sub)
+		} else {
+			kept = append(kept, sub)
+		}
+	}
+
+	s.included = kept
+	s.blocks = s.blocks[:forkPoint]
+	s.hashes = s.hashes[:forkPoint]
+	s.forkSalt++
+
+	for _, sub := range retracted {
+		sub.inBlock = 0
+		sub.notify(types.ExtrinsicStatus{IsRetracted: true})
+		if !reinclude {
+			sub.notify(types.ExtrinsicStatus{IsDropped: true})
+		}
+	}
+
+	if reinclude {
+		for _, sub := range retracted {
+			sub.includeAt = forkPoint
+		}
+		s.pending = append(retracted, s.pending...)
+	}
+
+	s.logger.Info("forking", "fork_point", forkPoint, "depth", depth, "retracted", len(retracted), "reinclude", reinclude)
+
+	for i := uint64(0); i <= depth; i++ {
+		s.produceBlock()
+	}
+
+	return nil
+}
+
+// Head returns the number of the latest block.
+func (s *Simulator) Head() uint64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.head()
+}
+
+// BlockStream creates a new Avail block stream, starting from the specified
+// block height offset. An offset of zero streams only blocks produced after
+// the call, the same way the gsrpc backed client does.
+func (s *Simulator) BlockStream(offset uint64) avail.BlockStream {
+	s.mtx.Lock()
+	next := offset
+	if next == 0 {
+		next = s.head() + 1
+	}
+	s.mtx.Unlock()
+
+	bs := &blockStream{
+		sim:     s,
+		closeCh: make(chan struct{}),
+		dataCh:  make(chan *types.SignedBlock),
+		next:    next,
+	}
+
+	go bs.run()
+
+	return bs
+}
+
+// GenesisHash returns the hash of the simulated genesis block.
+func (s *Simulator) GenesisHash() types.Hash {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.hashes[0]
+}
+
+// GetLatestHeader returns the header of the latest block.
+func (s *Simulator) GetLatestHeader() (*types.Header, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	hdr := s.blocks[s.head()].Block.Header
+	return &hdr, nil
+}
+
+// GetBlockByNumber returns the block at the specified height.
+func (s *Simulator) GetBlockByNumber(number uint64) (*types.SignedBlock, error) {
+	blk, _ := s.blockOrWait(number)
+	if blk == nil {
+		return nil, fmt.Errorf("%w: %d", ErrBlockNotFound, number)
+	}
+
+	return blk, nil
+}
+
+// SearchBlock searches for a block at the specified offset using the provided search function.
+func (s *Simulator) SearchBlock(offset int64, searchFunc avail.SearchFunc) (*types.SignedBlock, error) {
+	return avail.SearchBlocks(s, offset, searchFunc)
+}
+
+// SubmitDataCallIndex returns the `submit_data` call index used by the simulator.
+func (s *Simulator) SubmitDataCallIndex() (types.CallIndex, error) {
+	return s.callIdx, nil
+}
+
+// NewSender returns an avail.Sender that submits data to the simulator.
+func (s *Simulator) NewSender(appID types.UCompact, signingKeyPair signature.KeyringPair) avail.Sender {
+	return avail.NewSender(s, appID, signingKeyPair)
+}
+
+// SubmitData queues the `submit_data` payload for inclusion and returns the
+// channel delivering status updates of the resulting extrinsic.
+func (s *Simulator) SubmitData(appID types.UCompact, signer signature.KeyringPair, data []byte) (<-chan types.ExtrinsicStatus, error) {
+	select {
+	case <-s.closeCh:
+		return nil, ErrClosed
+	default:
+	}
+
+	args, err := codec.Encode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := types.NewMultiAddressFromAccountID(signer.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	nonce := s.nonces[string(signer.PublicKey)]
+	s.nonces[string(signer.PublicKey)] = nonce + 1
+
+	sub := &submission{
+		ext: types.Extrinsic{
+			Version: types.ExtrinsicVersion4 | types.ExtrinsicBitSigned,
+			Signature: types.ExtrinsicSignatureV4{
+				Signer: addr,
+				Nonce:  types.NewUCompactFromUInt(nonce),
+				AppID:  appID,
+			},
+			Method: types.Call{
+				CallIndex: s.callIdx,
+				Args:      types.Args(args),
+			},
+		},
+		includeAt: s.head() + 1 + s.inclusionDelay,
+		statusCh:  make(chan types.ExtrinsicStatus, 8),
+	}
+
+	if s.dropNext > 0 {
+		s.dropNext--
+		sub.notify(types.ExtrinsicStatus{IsDropped: true})
+		s.logger.Debug("dropping submitted extrinsic", "signer", signer.Address, "nonce", nonce)
+		return sub.statusCh, nil
+	}
+
+	sub.notify(types.ExtrinsicStatus{IsReady: true})
+	s.pending = append(s.pending, sub)
+
+	return sub.statusCh, nil
+}
+
+// AccountExists reports whether the account has ever been funded.
+func (s *Simulator) AccountExists(publicKey []byte) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	_, exists := s.balances[string(publicKey)]
+	return exists, nil
+}
+
+// FreeBalance returns the balance of the account in Avail fractions.
+func (s *Simulator) FreeBalance(publicKey []byte) (*big.Int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	balance, exists := s.balances[string(publicKey)]
+	if !exists {
+		return big.NewInt(0), nil
+	}
+
+	return new(big.Int).Set(balance), nil
+}
+
+// Transfer mints the amount of Avail fractions to the account.
+func (s *Simulator) Transfer(publicKey []byte, amount *big.Int) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	balance, exists := s.balances[string(publicKey)]
+	if !exists {
+		balance = big.NewInt(0)
+		s.balances[string(publicKey)] = balance
+	}
+
+	balance.Add(balance, amount)
+
+	return nil
+}
+
+// QueryAppID returns the AppID registered for the application key.
+func (s *Simulator) QueryAppID(applicationKey string) (types.UCompact, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	appID, exists := s.appKeys[applicationKey]
+	if !exists {
+		return types.NewUCompactFromUInt(0), avail.ErrAppIDNotFound
+	}
+
+	return appID, nil
+}
+
+// CreateApplicationKey registers the application key and returns its AppID.
+// Registering an already known key returns the existing AppID.
+func (s *Simulator) CreateApplicationKey(applicationKey string, _ []byte) (types.UCompact, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if appID, exists := s.appKeys[applicationKey]; exists {
+		return appID, nil
+	}
+
+	appID := types.NewUCompactFromUInt(s.nextAppID)
+	s.appKeys[applicationKey] = appID
+	s.nextAppID++
+
+	return appID, nil
+}
+
+// head returns the number of the latest block. Must be called with mtx held.
+func (s *Simulator) head() uint64 {
+	return uint64(len(s.blocks) - 1)
+}
+
+// produceBlock includes all due pending extrinsics in a new block, updates
+// finality and notifies block streams. Must be called with mtx held.
+func (s *Simulator) produceBlock() uint64 {
+	number := s.head() + 1
+
+	var (
+		exts    []types.Extrinsic
+		pending []*submission
+		inBlock []*submission
+	)
+
+	for _, sub := range s.pending {
+		if sub.includeAt > number {
+			pending = append(pending, sub)
+			continue
+		}
+
+		sub.inBlock = number
+		exts = append(exts, sub.ext)
+		inBlock = append(inBlock, sub)
+	}
+
+	s.pending = pending
+	s.appendBlock(exts)
+
+	for _, sub := range inBlock {
+		sub.notify(types.ExtrinsicStatus{IsInBlock: true, AsInBlock: s.hashes[number]})
+	}
+
+	s.included = append(s.included, inBlock...)
+	s.finalize(number)
+
+	close(s.headCh)
+	s.headCh = make(chan struct{})
+
+	return number
+}
+
+// finalize notifies submissions that became final with the head at the
+// given height. Must be called with mtx held.
+func (s *Simulator) finalize(head uint64) {
+	var unfinalized []*submission
+	for _, sub := range s.included {
+		if sub.inBlock+s.finalityDepth > head {
+			unfinalized = append(unfinalized, sub)
+			continue
+		}
+
+		sub.notify(types.ExtrinsicStatus{IsFinalized: true, AsFinalized: s.hashes[sub.inBlock]})
+	}
+
+	s.included = unfinalized
+}
+
+// appendBlock appends a new block with the extrinsics on top of the current
+// head. Must be called with mtx held.
+func (s *Simulator) appendBlock(exts []types.Extrinsic) {
+	var (
+		number     = uint64(len(s.blocks))
+		parentHash types.Hash
+	)
+
+	if number > 0 {
+		parentHash = s.hashes[number-1]
+	}
+
+	if exts == nil {
+		exts = []types.Extrinsic{}
+	}
+
+	blk := &types.SignedBlock{
+		Block: types.Block{
+			Header: types.Header{
+				ParentHash: parentHash,
+				Number:     types.BlockNumber(number),
+			},
+			Extrinsics: exts,
+		},
+	}
+
+	s.blocks = append(s.blocks, blk)
+	s.hashes = append(s.hashes, s.blockHash(parentHash, number, exts))
+}
+
+// blockHash computes a unique hash for the block contents. The fork salt
+// makes sure that blocks replaced by a fork get a different hash even if
+// they carry the same extrinsics.
+func (s *Simulator) blockHash(parentHash types.Hash, number uint64, exts []types.Extrinsic) types.Hash {
+	h := sha256.New()
+	h.Write(parentHash[:])
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], number)
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], s.forkSalt)
+	h.Write(buf[:])
+
+	for _, ext := range exts {
+		bs, err := codec.Encode(ext)
+		if err != nil {
+			s.logger.Error("failed to encode extrinsic for block hash", "block_number", number, "error", err)
+			continue
+		}
+		h.Write(bs)
+	}
+
+	return types.NewHash(h.Sum(nil))
+}
+
+// blockOrWait returns the block at the specified height, or, when it doesn't
+// exist yet, a channel that is closed once the next block is produced.
+func (s *Simulator) blockOrWait(number uint64) (*types.SignedBlock, <-chan struct{}) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if number > s.head() {
+		return nil, s.headCh
+	}
+
+	return s.blocks[number], nil
+}
@@ -0,0 +1,159 @@
+package simulator
+
+import (
+	"testing"
+	"time"
+
+	edge_types "github.com/0xPolygon/polygon-edge/types"
+	"github.com/availproject/op-evm/pkg/avail"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSender(t *testing.T, sim *Simulator) avail.Sender {
+	t.Helper()
+
+	account, err := avail.NewAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appID, err := avail.EnsureApplicationKeyExists(sim, avail.ApplicationKey, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return avail.NewSender(sim, appID, account)
+}
+
+func newTestBlock(number uint64) *edge_types.Block {
+	hdr := &edge_types.Header{
+		Number:    number,
+		ExtraData: []byte{},
+	}
+	hdr.ComputeHash()
+
+	return &edge_types.Block{Header: hdr}
+}
+
+// sendAsync submits the block and returns the channel with the result of
+// waiting for the expected status.
+func sendAsync(sender avail.Sender, blk *edge_types.Block, status types.ExtrinsicStatus) <-chan error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- sender.SendAndWaitForStatus(blk, status)
+	}()
+
+	return errCh
+}
+
+// waitForPending blocks until the simulator has n pending extrinsics.
+func waitForPending(t *testing.T, sim *Simulator, n int) {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		sim.mtx.Lock()
+		pending := len(sim.pending)
+		sim.mtx.Unlock()
+
+		if pending == n {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for %d pending extrinsics", n)
+}
+
+func Test_SimulatorIncludesSubmittedBlocks(t *testing.T) {
+	sim := New(hclog.NewNullLogger(), Config{})
+	defer sim.Close()
+
+	sender := newTestSender(t, sim)
+	stream := sim.BlockStream(0)
+	defer stream.Close()
+
+	blk := newTestBlock(7)
+	errCh := sendAsync(sender, blk, types.ExtrinsicStatus{IsInBlock: true})
+	waitForPending(t, sim, 1)
+
+	assert.Equal(t, uint64(1), sim.Tick())
+	assert.NoError(t, <-errCh)
+
+	availBlk := <-stream.Chan()
+	assert.Equal(t, types.BlockNumber(1), availBlk.Block.Header.Number)
+
+	callIdx, err := avail.FindCallIndex(sim)
+	assert.NoError(t, err)
+
+	appID, err := avail.QueryAppID(sim, avail.ApplicationKey)
+	assert.NoError(t, err)
+
+	blks, err := avail.BlockFromAvail(availBlk, appID, callIdx, hclog.NewNullLogger())
+	assert.NoError(t, err)
+	assert.Len(t, blks, 1)
+	assert.Equal(t, blk.Hash(), blks[0].Hash())
+
+	// Different AppID must not match.
+	_, err = avail.BlockFromAvail(availBlk, types.NewUCompactFromUInt(uint64(appID.Int64()+1)), callIdx, hclog.NewNullLogger())
+	assert.ErrorIs(t, err, avail.ErrNoExtrinsicFound)
+}
+
+func Test_SimulatorDropAndDelay(t *testing.T) {
+	sim := New(hclog.NewNullLogger(), Config{})
+	defer sim.Close()
+
+	sender := newTestSender(t, sim)
+
+	sim.DropNext(1)
+	assert.Error(t, sender.SendAndWaitForStatus(newTestBlock(1), types.ExtrinsicStatus{IsInBlock: true}))
+
+	sim.SetInclusionDelay(2)
+	errCh := sendAsync(sender, newTestBlock(2), types.ExtrinsicStatus{IsInBlock: true})
+	waitForPending(t, sim, 1)
+
+	sim.Advance(2)
+	select {
+	case err := <-errCh:
+		t.Fatalf("extrinsic included too early: %v", err)
+	default:
+	}
+
+	sim.Tick()
+	assert.NoError(t, <-errCh)
+}
+
+func Test_SimulatorFork(t *testing.T) {
+	sim := New(hclog.NewNullLogger(), Config{FinalityDepth: 3})
+	defer sim.Close()
+
+	sender := newTestSender(t, sim)
+
+	errCh := sendAsync(sender, newTestBlock(1), types.ExtrinsicStatus{IsFinalized: true})
+	waitForPending(t, sim, 1)
+	sim.Advance(2)
+
+	oldHead, err := sim.GetLatestHeader()
+	assert.NoError(t, err)
+	oldBlk, err := sim.GetBlockByNumber(1)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, sim.Fork(4, true), ErrForkBeyondFinality)
+	assert.NoError(t, sim.Fork(2, true))
+
+	newHead, err := sim.GetLatestHeader()
+	assert.NoError(t, err)
+	assert.Equal(t, oldHead.Number+1, newHead.Number)
+
+	newBlk, err := sim.GetBlockByNumber(1)
+	assert.NoError(t, err)
+	assert.Len(t, newBlk.Block.Extrinsics, 1)
+	assert.NotEqual(t, oldBlk.Block.Header.ParentHash, types.Hash{})
+	assert.Equal(t, oldBlk.Block.Header.ParentHash, newBlk.Block.Header.ParentHash)
+
+	// The re-included extrinsic becomes final once three blocks are on top.
+	sim.Advance(1)
+	assert.NoError(t, <-errCh)
+}
@@ -0,0 +1,58 @@
+package simulator
+
+import (
+	"sync/atomic"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+// blockStream implements avail.BlockStream on top of the simulator.
+type blockStream struct {
+	sim     *Simulator
+	closed  atomic.Bool
+	closeCh chan struct{}
+	dataCh  chan *types.SignedBlock
+	next    uint64
+}
+
+// Chan returns the channel on which the signed blocks are received.
+func (bs *blockStream) Chan() <-chan *types.SignedBlock {
+	return bs.dataCh
+}
+
+// Close closes the block stream.
+func (bs *blockStream) Close() {
+	if bs.closed.CompareAndSwap(false, true) {
+		close(bs.closeCh)
+	}
+}
+
+// run delivers blocks in order, waiting for new ones once it reaches the
+// head. Like the gsrpc backed stream, a block height is delivered only once,
+// even if it's later replaced by a fork.
+func (bs *blockStream) run() {
+	defer close(bs.dataCh)
+
+	for {
+		blk, headCh := bs.sim.blockOrWait(bs.next)
+		if blk == nil {
+			select {
+			case <-headCh:
+				continue
+			case <-bs.closeCh:
+				return
+			case <-bs.sim.closeCh:
+				return
+			}
+		}
+
+		select {
+		case bs.dataCh <- blk:
+			bs.next++
+		case <-bs.closeCh:
+			return
+		case <-bs.sim.closeCh:
+			return
+		}
+	}
+}
@@ -57,9 +57,11 @@ type instance struct {
 
 // StartNodes starts the devnet nodes based on the provided parameters.
 // It creates the Avail accounts, configures and starts the nodes, and returns the devnet context.
-func StartNodes(logger hclog.Logger, bindAddr netip.Addr, availAddr, accountsPath string, nodeTypes ...consensus.MechanismType) (*Context, error) {
+// All nodes share the given Avail client, which can be a live Avail node
+// connection or an in-memory Avail simulator.
+func StartNodes(logger hclog.Logger, bindAddr netip.Addr, availClient avail.Client, accountsPath string, nodeTypes ...consensus.MechanismType) (*Context, error) {
 	ctx := &Context{}
-	if err := createAvailAccounts(logger, availAddr, accountsPath, nodeTypes); err != nil {
+	if err := createAvailAccounts(logger, availClient, accountsPath, nodeTypes); err != nil {
 		return nil, err
 	}
 
@@ -134,7 +136,7 @@ func StartNodes(logger hclog.Logger, bindAddr netip.Addr, availAddr, accountsPat
 		si.config.Chain.Bootnodes = []string{bootnodeAddr}
 		si.config.Network.Chain.Bootnodes = []string{bootnodeAddr}
 
-		srv, err := startNode(logger, si.config, availAddr, si.accountPath, si.fraudAddr, si.nodeType)
+		srv, err := startNode(logger, si.config, availClient, si.accountPath, si.fraudAddr, si.nodeType)
 		if err != nil {
 			return nil, err
 		}
@@ -262,9 +264,9 @@ func configureNode(pa *PortAllocator, nodeType consensus.MechanismType) (*pkg_co
 	return cfg, nil
 }
 
-// startNode starts a devnet node based on the provided config, Avail address, account path, fraud listener address, and node type.
+// startNode starts a devnet node based on the provided config, Avail client, account path, fraud listener address, and node type.
 // It returns the started server instance.
-func startNode(logger hclog.Logger, cfg *edge_server.Config, availAddr, accountPath, fraudListenerAddr string, nodeType consensus.MechanismType) (*server.Server, error) {
+func startNode(logger hclog.Logger, cfg *edge_server.Config, availClient avail.Client, accountPath, fraudListenerAddr string, nodeType consensus.MechanismType) (*server.Server, error) {
 	var bootnode bool
 	if nodeType == consensus.BootstrapSequencer {
 		bootnode = true
@@ -275,17 +277,12 @@ func startNode(logger hclog.Logger, cfg *edge_server.Config, availAddr, accountP
 		log.Fatalf("failed to read Avail account from %q: %s\n", accountPath, err)
 	}
 
-	availClient, err := avail.NewClient(availAddr, logger)
-	if err != nil {
-		log.Fatalf("failed to create Avail client: %s\n", err)
-	}
-
 	appID, err := avail.EnsureApplicationKeyExists(availClient, avail.ApplicationKey, availAccount)
 	if err != nil {
 		log.Fatalf("failed to get AppID from Avail: %s\n", err)
 	}
 
-	availSender := avail.NewSender(availClient, appID, availAccount)
+	availSender := avail.NewSenderWithConfig(availClient, appID, availAccount, avail.SenderConfig{Codec: avail.CodecZstd})
 
 	consensusCfg := consensus.Config{
 		Bootnode:          bootnode,
@@ -393,16 +390,11 @@ func (sc *Context) FirstRPCAddrForNodeType(nodeType consensus.MechanismType) (*n
 }
 
 // createAvailAccounts creates the Avail accounts for the devnet nodes.
-func createAvailAccounts(logger hclog.Logger, availAddr, accountPath string, nodeTypes []consensus.MechanismType) error {
+func createAvailAccounts(logger hclog.Logger, availClient avail.Client, accountPath string, nodeTypes []consensus.MechanismType) error {
 	nnh := newNodeNameHelper(accountPath)
 
 	var accountWg sync.WaitGroup
 
-	availClient, err := avail.NewClient(availAddr, logger)
-	if err != nil {
-		return err
-	}
-
 	var nonceIncrement uint64
 	errCh := make(chan error)
 	for _, nt := range nodeTypes {
@@ -23,8 +23,6 @@ import (
 const privateKeyBytes = "e29fc399e151b829ca68ba811108965aeec52c21f2ac1744cb28f203231dc085"
 
 func Benchmark_SendingTransactions(b *testing.B) {
-	b.Skip("multi-sequencer benchmarks disabled in CI/CD due to lack of Avail")
-
 	flag.Parse()
 
 	addr, err := netip.ParseAddr(*bindInterface)
@@ -35,7 +33,7 @@ func Benchmark_SendingTransactions(b *testing.B) {
 	var ethClient *ethclient.Client
 	if *bootnodeAddr == "" {
 		b.Log("starting nodes")
-		ctx, err := devnet.StartNodes(hclog.Default(), addr, *availAddr, *accountPath, avail.BootstrapSequencer, avail.Sequencer, avail.Sequencer, avail.WatchTower)
+		ctx, err := devnet.StartNodes(hclog.Default(), addr, newAvailClient(b), *accountPath, avail.BootstrapSequencer, avail.Sequencer, avail.Sequencer, avail.WatchTower)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -14,7 +14,9 @@ import (
 )
 
 func Test_Fraud(t *testing.T) {
-	t.Skip("fraud e2e tests disabled in CI/CD due to lack of Avail")
+	if testing.Short() {
+		t.Skip("fraud e2e tests disabled in short mode")
+	}
 
 	flag.Parse()
 
@@ -26,7 +28,7 @@ func Test_Fraud(t *testing.T) {
 	}
 	var ethClient *ethclient.Client
 	if *bootnodeAddr == "" {
-		ctx, err := devnet.StartNodes(hclog.Default(), bindAddr, *availAddr, *accountPath, avail.BootstrapSequencer, avail.Sequencer, avail.WatchTower)
+		ctx, err := devnet.StartNodes(hclog.Default(), bindAddr, newAvailClient(t), *accountPath, avail.BootstrapSequencer, avail.Sequencer, avail.WatchTower)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -11,11 +11,13 @@ import (
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/availproject/op-evm/consensus/avail"
+	pkg_avail "github.com/availproject/op-evm/pkg/avail"
+	"github.com/availproject/op-evm/pkg/avail/simulator"
 	"github.com/availproject/op-evm/pkg/devnet"
 )
 
 // nolint:unused
-var availAddr = flag.String("avail-addr", "ws://127.0.0.1:9944/v1/json-rpc", "Avail JSON-RPC URL")
+var availAddr = flag.String("avail-addr", "", "Avail JSON-RPC URL (e.g. ws://127.0.0.1:9944/v1/json-rpc); an in-memory Avail simulator is used when empty")
 
 // nolint:unused
 var bindInterface = flag.String("bind-addr", "127.0.0.1", "IP address of the interface to bind node ports to")
@@ -27,7 +29,9 @@ var accountPath = flag.String("account-path", "../data/test-accounts", "Path to
 var bootnodeAddr = flag.String("bootnode-addr", "", "Remote bootstrap sequencer address")
 
 func Test_MultipleSequencers(t *testing.T) {
-	t.Skip("multi-sequencer e2e tests disabled in CI/CD due to lack of Avail")
+	if testing.Short() {
+		t.Skip("multi-sequencer e2e tests disabled in short mode")
+	}
 
 	flag.Parse()
 
@@ -41,7 +45,7 @@ func Test_MultipleSequencers(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		ctx, err := devnet.StartNodes(hclog.Default(), bindAddr, *availAddr, *accountPath, avail.BootstrapSequencer, avail.Sequencer, avail.Sequencer, avail.WatchTower)
+		ctx, err := devnet.StartNodes(hclog.Default(), bindAddr, newAvailClient(t), *accountPath, avail.BootstrapSequencer, avail.Sequencer, avail.Sequencer, avail.WatchTower)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -80,6 +84,29 @@ func Test_MultipleSequencers(t *testing.T) {
 	}
 }
 
+// newAvailClient returns a client for the Avail node given with the
+// `-avail-addr` flag, or an in-memory Avail simulator when there is none.
+//
+// nolint:unused
+func newAvailClient(t testing.TB) pkg_avail.Client {
+	t.Helper()
+
+	if *availAddr == "" {
+		sim := simulator.New(hclog.Default(), simulator.Config{BlockTime: 2 * time.Second})
+		sim.Start()
+		t.Cleanup(sim.Close)
+
+		return sim
+	}
+
+	client, err := pkg_avail.NewClient(*availAddr, hclog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
 // nolint:unused
 func waitForPeers(t testing.TB, ethClient *ethclient.Client, minNodes int) {
 	t.Helper()